@@ -27,7 +27,7 @@ func (s StringValue) ToInt64() (int64, error) {
 type Context struct {
 	Req              *http.Request
 	Resp             http.ResponseWriter
-	PathParams       map[string]string
+	PathParams       []Param
 	cacheQueryValues url.Values
 }
 
@@ -60,16 +60,19 @@ func (c *Context) QueryValue(key string) StringValue {
 }
 
 func (c *Context) PathValue(key string) StringValue {
-	val, ok := c.PathParams[key]
-	if !ok {
-		return StringValue{
-			val: "",
-			err: errors.New("web: 找不到这个key"),
+	// 从后往前找：同名路径参数以最后一次匹配为准，
+	// 例如 /user/:id/abc/:id 命中 /user/123/abc/456，id 最终是 456
+	for i := len(c.PathParams) - 1; i >= 0; i-- {
+		if c.PathParams[i].Key == key {
+			return StringValue{
+				val: c.PathParams[i].Value,
+				err: nil,
+			}
 		}
 	}
 	return StringValue{
-		val: val,
-		err: nil,
+		val: "",
+		err: errors.New("web: 找不到这个key"),
 	}
 }
 
@@ -0,0 +1,21 @@
+package web
+
+import "testing"
+
+func TestContext_PathValue_DuplicateParamName(t *testing.T) {
+	// /user/:id/abc/:id 命中 /user/123/abc/456，按文档约定同名参数以最后一次为准，id 应该是 456
+	ctx := &Context{
+		PathParams: []Param{
+			{Key: "id", Value: "123"},
+			{Key: "id", Value: "456"},
+		},
+	}
+
+	got, err := ctx.PathValue("id").String()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "456" {
+		t.Fatalf("want 456, got %s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package web
+
+import "net/http"
+
+// Middleware 是在 HandleFunc 执行前后插入额外逻辑的装饰器，
+// 多个 Middleware 按照注册顺序组合，先注册的先执行
+type Middleware func(next HandleFunc) HandleFunc
+
+// RouterGroup 把一组路由聚合在同一个路径前缀和中间件栈下，
+// 支持嵌套，子分组会继承父分组已经注册的中间件
+type RouterGroup struct {
+	prefix      string
+	server      *HTTPServer
+	middlewares []Middleware
+}
+
+// Use 给当前分组追加中间件，不影响已经创建的子分组
+func (g *RouterGroup) Use(mws ...Middleware) {
+	g.middlewares = append(g.middlewares, mws...)
+}
+
+// Group 在当前分组下创建子分组，prefix 会拼接在父分组 prefix 之后
+func (g *RouterGroup) Group(prefix string) *RouterGroup {
+	return &RouterGroup{
+		prefix:      g.prefix + prefix,
+		server:      g.server,
+		middlewares: append([]Middleware{}, g.middlewares...),
+	}
+}
+
+func (g *RouterGroup) handle(method string, path string, handler HandleFunc, opts ...RouteOption) {
+	g.server.addRoute(method, g.prefix+path, applyMiddlewares(handler, g.middlewares), opts...)
+}
+
+func (g *RouterGroup) Get(path string, handler HandleFunc, opts ...RouteOption) {
+	g.handle(http.MethodGet, path, handler, opts...)
+}
+
+func (g *RouterGroup) Post(path string, handler HandleFunc, opts ...RouteOption) {
+	g.handle(http.MethodPost, path, handler, opts...)
+}
+
+func (g *RouterGroup) Put(path string, handler HandleFunc, opts ...RouteOption) {
+	g.handle(http.MethodPut, path, handler, opts...)
+}
+
+func (g *RouterGroup) Delete(path string, handler HandleFunc, opts ...RouteOption) {
+	g.handle(http.MethodDelete, path, handler, opts...)
+}
+
+// applyMiddlewares 把中间件按注册顺序组合成最终的 HandleFunc，
+// 只在注册阶段调用一次，匹配到的 *node 上保存的就是组合好的结果，请求处理时不会重新计算
+func applyMiddlewares(h HandleFunc, mws []Middleware) HandleFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouterGroup(t *testing.T) {
+	trace := func(name string) Middleware {
+		return func(next HandleFunc) HandleFunc {
+			return func(ctx *Context) {
+				ctx.Resp.Write([]byte(name + ">"))
+				next(ctx)
+				ctx.Resp.Write([]byte("<" + name))
+			}
+		}
+	}
+
+	t.Run("全局中间件作用于直接注册的路由", func(t *testing.T) {
+		s := NewHTTPServer()
+		s.Use(trace("global"))
+		s.Get("/ping", func(ctx *Context) {
+			ctx.Resp.Write([]byte("pong"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if got, want := w.Body.String(), "global>pong<global"; got != want {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+	})
+
+	t.Run("分组中间件按注册顺序组合并且只作用于本分组", func(t *testing.T) {
+		s := NewHTTPServer()
+		g := s.Group("/api")
+		g.Use(trace("outer"), trace("inner"))
+		g.Get("/users", func(ctx *Context) {
+			ctx.Resp.Write([]byte("users"))
+		})
+		s.Get("/health", func(ctx *Context) {
+			ctx.Resp.Write([]byte("ok"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		if got, want := w.Body.String(), "outer>inner>users<inner<outer"; got != want {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+
+		req2 := httptest.NewRequest(http.MethodGet, "/health", nil)
+		w2 := httptest.NewRecorder()
+		s.ServeHTTP(w2, req2)
+		if got, want := w2.Body.String(), "ok"; got != want {
+			t.Fatalf("/health 不应该被分组中间件影响, want %s, got %s", want, got)
+		}
+	})
+
+	t.Run("嵌套分组继承父分组中间件并拼接前缀", func(t *testing.T) {
+		s := NewHTTPServer()
+		s.Use(trace("global"))
+		api := s.Group("/api")
+		api.Use(trace("api"))
+		v1 := api.Group("/v1")
+		v1.Use(trace("v1"))
+		v1.Get("/users", func(ctx *Context) {
+			ctx.Resp.Write([]byte("users"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		want := "global>api>v1>users<v1<api<global"
+		if got := w.Body.String(); got != want {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+	})
+
+	t.Run("分组之后追加的全局中间件不会作用于已创建的分组", func(t *testing.T) {
+		s := NewHTTPServer()
+		g := s.Group("/api")
+		s.Use(trace("late-global"))
+		g.Get("/users", func(ctx *Context) {
+			ctx.Resp.Write([]byte("users"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if got := w.Body.String(); strings.Contains(got, "late-global") {
+			t.Fatalf("分组不应该看到创建之后才注册的全局中间件, got %s", got)
+		}
+		if got, want := w.Body.String(), "users"; got != want {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+	})
+}
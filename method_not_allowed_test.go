@@ -0,0 +1,90 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPServer_HandleMethodNotAllowed(t *testing.T) {
+	newServer := func(opts ...ServerOption) *HTTPServer {
+		s := NewHTTPServer(opts...)
+		s.Get("/user", func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		})
+		s.Post("/user", func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		})
+		return s
+	}
+
+	t.Run("关闭时命中路径但方法不对也是404", func(t *testing.T) {
+		s := newServer()
+		req := httptest.NewRequest(http.MethodDelete, "/user", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+		if w.Header().Get("Allow") != "" {
+			t.Fatalf("want no Allow header, got %q", w.Header().Get("Allow"))
+		}
+	})
+
+	t.Run("开启后命中路径但方法不对返回405和Allow", func(t *testing.T) {
+		s := newServer(WithHandleMethodNotAllowed())
+		req := httptest.NewRequest(http.MethodDelete, "/user", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("want 405, got %d", w.Code)
+		}
+
+		allow := w.Header().Get("Allow")
+		if allow != "GET, POST" && allow != "POST, GET" {
+			t.Fatalf("want Allow header listing GET and POST, got %q", allow)
+		}
+	})
+
+	t.Run("开启后路径本身没注册依然是404", func(t *testing.T) {
+		s := newServer(WithHandleMethodNotAllowed())
+		req := httptest.NewRequest(http.MethodGet, "/not-registered", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("自定义405处理函数", func(t *testing.T) {
+		s := newServer(
+			WithHandleMethodNotAllowed(),
+			WithMethodNotAllowedHandler(func(ctx *Context) {
+				ctx.Resp.WriteHeader(http.StatusTeapot)
+			}),
+		)
+		req := httptest.NewRequest(http.MethodDelete, "/user", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusTeapot {
+			t.Fatalf("want 418, got %d", w.Code)
+		}
+	})
+
+	t.Run("自定义404处理函数", func(t *testing.T) {
+		s := newServer(WithNotFoundHandler(func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusTeapot)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/not-registered", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusTeapot {
+			t.Fatalf("want 418, got %d", w.Code)
+		}
+	})
+}
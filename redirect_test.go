@@ -0,0 +1,105 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPServer_RedirectTrailingSlash(t *testing.T) {
+	newServer := func(opts ...ServerOption) *HTTPServer {
+		s := NewHTTPServer(opts...)
+		s.Get("/user", func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		})
+		s.Post("/order", func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		})
+		return s
+	}
+
+	t.Run("关闭时多余的末尾斜杠是404", func(t *testing.T) {
+		s := newServer()
+		req := httptest.NewRequest(http.MethodGet, "/user/", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("开启后GET多余的末尾斜杠发起301", func(t *testing.T) {
+		s := newServer(WithRedirectTrailingSlash())
+		req := httptest.NewRequest(http.MethodGet, "/user/", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusMovedPermanently {
+			t.Fatalf("want 301, got %d", w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/user" {
+			t.Fatalf("want redirect to /user, got %s", got)
+		}
+	})
+
+	t.Run("开启后非GET多余的末尾斜杠发起308", func(t *testing.T) {
+		s := newServer(WithRedirectTrailingSlash())
+		req := httptest.NewRequest(http.MethodPost, "/order/", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusPermanentRedirect {
+			t.Fatalf("want 308, got %d", w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/order" {
+			t.Fatalf("want redirect to /order, got %s", got)
+		}
+	})
+
+	t.Run("开启后根本不存在的路径依然404", func(t *testing.T) {
+		s := newServer(WithRedirectTrailingSlash())
+		req := httptest.NewRequest(http.MethodGet, "/not-registered/", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestHTTPServer_RedirectFixedPath(t *testing.T) {
+	newServer := func(opts ...ServerOption) *HTTPServer {
+		s := NewHTTPServer(opts...)
+		s.Get("/User/Profile", func(ctx *Context) {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		})
+		return s
+	}
+
+	t.Run("关闭时大小写不一致是404", func(t *testing.T) {
+		s := newServer()
+		req := httptest.NewRequest(http.MethodGet, "/user/profile", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("开启后大小写不一致会被重定向到规范路径", func(t *testing.T) {
+		s := newServer(WithRedirectFixedPath())
+		req := httptest.NewRequest(http.MethodGet, "/user//profile", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusMovedPermanently {
+			t.Fatalf("want 301, got %d", w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/User/Profile" {
+			t.Fatalf("want redirect to /User/Profile, got %s", got)
+		}
+	})
+}
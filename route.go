@@ -2,6 +2,7 @@ package web
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 )
@@ -10,6 +11,13 @@ type router struct {
 	// trees 是按照 HTTP 方法来组织的
 	// 如 GET => *node
 	trees map[string]*node
+
+	// maxParams 是目前为止注册过的路由里，:param/正则/* 这类动态段最多的一条路由的段数，
+	// 用来在创建 Context 的时候预先分配好 pathParams 的容量，减少请求处理时的扩容开销
+	maxParams int
+
+	// names 保存通过 WithName 注册的命名路由，用来支撑反向路由 URL 生成
+	names map[string]*node
 }
 
 func newRouter() router {
@@ -18,6 +26,16 @@ func newRouter() router {
 	}
 }
 
+// RouteOption 是注册路由时的可选配置，例如 WithName
+type RouteOption func(n *node)
+
+// WithName 给路由取一个名字，支持之后通过 router.urlFor / Server.URLFor 反向生成 URL
+func WithName(name string) RouteOption {
+	return func(n *node) {
+		n.name = name
+	}
+}
+
 // addRoute 注册路由。
 // method 是 HTTP 方法
 // - 已经注册了的路由，无法被覆盖。例如 /user/home 注册两次，会冲突
@@ -25,7 +43,7 @@ func newRouter() router {
 // - 不能在同一个位置注册不同的参数路由，例如 /user/:id 和 /user/:name 冲突
 // - 不能在同一个位置同时注册通配符路由和参数路由，例如 /user/:id 和 /user/* 冲突
 // - 同名路径参数，在路由匹配的时候，值会被覆盖。例如 /user/:id/abc/:id，那么 /user/123/abc/456 最终 id = 456
-func (r *router) addRoute(method string, path string, handler HandleFunc) {
+func (r *router) addRoute(method string, path string, handler HandleFunc, opts ...RouteOption) {
 	if path == "" {
 		panic("web: 路由是空字符串")
 	}
@@ -44,34 +62,194 @@ func (r *router) addRoute(method string, path string, handler HandleFunc) {
 		r.trees[method] = root
 	}
 
+	var target *node
 	if path == "/" {
 		if root.handler != nil {
 			panic("web: 路由冲突[/]")
 		}
-		root.handler = handler
-		return
-	}
+		target = root
+	} else {
+		seqs := strings.Split(path[1:], "/")
+		paramCount := 0
+		cur := root
+		for _, s := range seqs {
+			if s == "" {
+				panic(fmt.Sprintf("web: 非法路由。不允许使用 //a/b, /a//b 之类的路由, [%s]", path))
+			}
+
+			if s == "*" || s[0] == ':' {
+				paramCount++
+			}
+
+			cur = cur.childOrCreate(s)
+		}
 
-	seqs := strings.Split(path[1:], "/")
-	for _, s := range seqs {
-		if s == "" {
-			panic(fmt.Sprintf("web: 非法路由。不允许使用 //a/b, /a//b 之类的路由, [%s]", path))
+		if cur.handler != nil {
+			panic(fmt.Sprintf("web: 路由冲突[%s]", path))
 		}
 
-		root = root.childOrCreate(s)
+		if paramCount > r.maxParams {
+			r.maxParams = paramCount
+		}
+		target = cur
 	}
 
-	if root.handler != nil {
-		panic(fmt.Sprintf("web: 路由冲突[%s]", path))
+	target.handler = handler
+	target.method = method
+	target.fullPath = path
+
+	for _, opt := range opts {
+		opt(target)
 	}
-	root.handler = handler
 
+	if target.name != "" {
+		if r.names == nil {
+			r.names = map[string]*node{}
+		}
+		if _, ok := r.names[target.name]; ok {
+			panic(fmt.Sprintf("web: 路由名字冲突[%s]", target.name))
+		}
+		r.names[target.name] = target
+	}
 }
 
 // findRoute 查找对应的节点
 // 注意，返回的 node 内部 HandleFunc 不为 nil 才算是注册了路由
 func (r *router) findRoute(method string, path string) (*matchInfo, bool) {
-	panic("implement me")
+	return r.findRouteWithParams(method, path, nil)
+}
+
+// findRouteWithParams 和 findRoute 效果一致，但允许调用方传入一个复用的 paramsBuf，
+// 命中的路径参数会 append 在这个 buf 上，避免每次请求都重新分配底层数组。
+// Server.serve 会把 Context 池化复用的 pathParams 切片传进来。
+func (r *router) findRouteWithParams(method string, path string, paramsBuf []Param) (*matchInfo, bool) {
+	root, ok := r.trees[method]
+	if !ok {
+		return nil, false
+	}
+
+	if path == "/" {
+		return &matchInfo{n: root, pathParams: paramsBuf[:0]}, true
+	}
+
+	mi := &matchInfo{pathParams: paramsBuf[:0]}
+	cur := root
+	// 只去掉已经校验过的开头 /，末尾的 / 必须保留，否则 /x 和 /x/ 会被当成同一条路由，
+	// RedirectTrailingSlash 就永远没有机会触发（两者在这里已经"命中"了）
+	segs := strings.Split(path[1:], "/")
+	for i, s := range segs {
+		child, ok := cur.childOf(s)
+		if !ok {
+			return nil, false
+		}
+
+		switch child.typ {
+		case nodeTypeReg, nodeTypeParam:
+			mi.addValue(child.paramName, s)
+		case nodeTypeAny:
+			// 通配符是贪婪匹配，剩下的所有路径段都归它所有
+			mi.addValue("*", strings.Join(segs[i:], "/"))
+			cur = child
+			mi.n = cur
+			return mi, true
+		}
+
+		cur = child
+	}
+
+	mi.n = cur
+	return mi, true
+}
+
+// findTrailingSlashRedirect 在 findRoute 失败的时候尝试去掉请求路径末尾多余的 /，
+// 如果能命中一个注册了 HandleFunc 的节点，就返回修正后的 path。
+// 注意：addRoute 禁止注册以 / 结尾的路由（/ 本身除外），所以"注册的是 /结尾路由，请求缺斜杠"
+// 这个方向永远不会发生，这里只处理请求比注册路径多了一个末尾 / 的情况。
+func (r *router) findTrailingSlashRedirect(method string, path string) (string, bool) {
+	if path == "/" || path[len(path)-1] != '/' {
+		return "", false
+	}
+
+	candidate := path[:len(path)-1]
+	mi, ok := r.findRoute(method, candidate)
+	if !ok || mi.n == nil || mi.n.handler == nil {
+		return "", false
+	}
+	return candidate, true
+}
+
+// findFixedPathRedirect 清理路径（折叠连续的 /，处理 ..），并在清理后依然找不到的情况下
+// 对路由树做一次大小写不敏感的遍历，尝试找到能够命中的规范路径。
+// 每一层优先完全匹配；有多个忽略大小写后相同的静态子节点时取其中任意一个，并且不回溯。
+// 这个遍历只会在 findRoute 命中失败之后才会触发，不影响正常请求路径的性能。
+func (r *router) findFixedPathRedirect(method string, path string) (string, bool) {
+	root, ok := r.trees[method]
+	if !ok {
+		return "", false
+	}
+
+	cleaned := cleanPath(path)
+
+	if cleaned == "/" {
+		if root.handler != nil {
+			return "/", true
+		}
+		return "", false
+	}
+
+	segs := strings.Split(strings.Trim(cleaned, "/"), "/")
+	fixed := make([]string, 0, len(segs))
+	cur := root
+	for i, s := range segs {
+		child, matchedSeg, matched := cur.childOfFoldCase(s)
+		if !matched {
+			return "", false
+		}
+
+		switch child.typ {
+		case nodeTypeAny:
+			fixed = append(fixed, segs[i:]...)
+			cur = child
+			return "/" + strings.Join(fixed, "/"), cur.handler != nil
+		case nodeTypeReg, nodeTypeParam:
+			fixed = append(fixed, s)
+		default:
+			fixed = append(fixed, matchedSeg)
+		}
+		cur = child
+	}
+
+	if cur.handler == nil {
+		return "", false
+	}
+	return "/" + strings.Join(fixed, "/"), true
+}
+
+// cleanPath 折叠 path 中连续的 /，并解析 .. 和 .，返回的路径总是以 / 开头，
+// 除了根路径以外不会带末尾的 /
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	return path.Clean(p)
+}
+
+// findAllowedMethods 返回除了 method 以外，其它注册了 path 这个路由的 HTTP 方法
+// 主要用来支撑 HandleMethodNotAllowed：命中路径但是方法不对时，告诉调用方还支持哪些方法
+func (r *router) findAllowedMethods(method string, path string) []string {
+	var allowed []string
+	for m := range r.trees {
+		if m == method {
+			continue
+		}
+		if mi, ok := r.findRoute(m, path); ok && mi.n != nil && mi.n.handler != nil {
+			allowed = append(allowed, m)
+		}
+	}
+	return allowed
 }
 
 type nodeType int
@@ -114,13 +292,42 @@ type node struct {
 	// 正则表达式
 	regChild *node
 	regExpr  *regexp.Regexp
+
+	// name 是通过 WithName 注册的路由名字，未命名则为空字符串
+	name string
+	// method 是注册这个终结点时使用的 HTTP 方法，供反向路由使用
+	method string
+	// fullPath 是注册这个终结点时使用的原始 path pattern，供反向路由使用
+	fullPath string
 }
 
 // child 返回子节点
 // 第一个返回值 *node 是命中的节点
 // 第二个返回值 bool 代表是否命中
 func (n *node) childOf(path string) (*node, bool) {
-	panic("implement me")
+	// 静态完全匹配
+	if n.children != nil {
+		if child, ok := n.children[path]; ok {
+			return child, true
+		}
+	}
+
+	// 正则匹配
+	if n.regChild != nil && n.regChild.regExpr != nil && n.regChild.regExpr.MatchString(path) {
+		return n.regChild, true
+	}
+
+	// 路径参数匹配
+	if n.paramChild != nil {
+		return n.paramChild, true
+	}
+
+	// 通配符匹配
+	if n.starChild != nil {
+		return n.starChild, true
+	}
+
+	return nil, false
 }
 
 // childOrCreate 查找子节点，如果不存在则创建一个
@@ -201,15 +408,47 @@ func (n *node) childOrCreate(path string) *node {
 	return target
 }
 
+// childOfFoldCase 和 childOf 类似，但静态匹配时忽略大小写。
+// 第二个返回值是命中的子节点在树里保存的原始（大小写正确的）path，用来拼出规范路径。
+// 只会在 RedirectFixedPath 触发的修正流程里调用，不在正常请求的热路径上。
+func (n *node) childOfFoldCase(path string) (*node, string, bool) {
+	if n.children != nil {
+		if child, ok := n.children[path]; ok {
+			return child, path, true
+		}
+		for seg, child := range n.children {
+			if strings.EqualFold(seg, path) {
+				return child, seg, true
+			}
+		}
+	}
+
+	if n.regChild != nil && n.regChild.regExpr != nil && n.regChild.regExpr.MatchString(path) {
+		return n.regChild, path, true
+	}
+
+	if n.paramChild != nil {
+		return n.paramChild, path, true
+	}
+
+	if n.starChild != nil {
+		return n.starChild, path, true
+	}
+
+	return nil, "", false
+}
+
+// Param 是一个命中的路径参数
+type Param struct {
+	Key   string
+	Value string
+}
+
 type matchInfo struct {
 	n          *node
-	pathParams map[string]string
+	pathParams []Param
 }
 
 func (m *matchInfo) addValue(key string, value string) {
-	if m.pathParams == nil {
-		// 大多数情况，参数路径只会有一段
-		m.pathParams = map[string]string{key: value}
-	}
-	m.pathParams[key] = value
+	m.pathParams = append(m.pathParams, Param{Key: key, Value: value})
 }
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_findRoute(t *testing.T) {
+	r := newRouter()
+	r.addRoute(http.MethodGet, "/user/home", func(ctx *Context) {})
+	r.addRoute(http.MethodGet, "/user/:id(^[0-9]+$)", func(ctx *Context) {})
+	r.addRoute(http.MethodGet, "/order/:id", func(ctx *Context) {})
+	r.addRoute(http.MethodGet, "/static/*", func(ctx *Context) {})
+
+	testCases := []struct {
+		name       string
+		path       string
+		wantFound  bool
+		wantParams map[string]string
+	}{
+		{
+			name:      "静态路由优先于正则路由",
+			path:      "/user/home",
+			wantFound: true,
+		},
+		{
+			name:      "正则路由匹配",
+			path:      "/user/123",
+			wantFound: true,
+			wantParams: map[string]string{
+				"id": "123",
+			},
+		},
+		{
+			name:      "不满足正则就匹配不到",
+			path:      "/user/abc",
+			wantFound: false,
+		},
+		{
+			name:      "参数路由匹配",
+			path:      "/order/abc",
+			wantFound: true,
+			wantParams: map[string]string{
+				"id": "abc",
+			},
+		},
+		{
+			name:      "通配符贪婪匹配整个子路径",
+			path:      "/static/js/app.js",
+			wantFound: true,
+			wantParams: map[string]string{
+				"*": "js/app.js",
+			},
+		},
+		{
+			name:      "没有命中任何节点",
+			path:      "/not/registered",
+			wantFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mi, ok := r.findRoute(http.MethodGet, tc.path)
+			if !ok {
+				if tc.wantFound {
+					t.Fatalf("want found, got not found")
+				}
+				return
+			}
+			if !tc.wantFound {
+				if mi.n != nil && mi.n.handler != nil {
+					t.Fatalf("want not found, but matched a registered handler")
+				}
+				return
+			}
+			if mi.n == nil || mi.n.handler == nil {
+				t.Fatalf("expected a node with a handler")
+			}
+			for k, v := range tc.wantParams {
+				got := ""
+				for _, p := range mi.pathParams {
+					if p.Key == k {
+						got = p.Value
+					}
+				}
+				if got != v {
+					t.Fatalf("param %s: want %s, got %s", k, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPServer_ServeHTTP(t *testing.T) {
+	s := NewHTTPServer()
+	s.Get("/user/:id", func(ctx *Context) {
+		id, _ := ctx.PathValue("id").String()
+		ctx.Resp.WriteHeader(http.StatusOK)
+		_, _ = ctx.Resp.Write([]byte(id))
+	})
+
+	t.Run("命中路由", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/user/123", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want 200, got %d", w.Code)
+		}
+		if w.Body.String() != "123" {
+			t.Fatalf("want 123, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("未命中返回404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/not-registered", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("want 404, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Server 是对 http.Handler 的封装，额外提供路由注册能力
+type Server interface {
+	http.Handler
+	Start(addr string) error
+
+	addRoute(method string, path string, handler HandleFunc, opts ...RouteOption)
+}
+
+// HTTPServer 是 Server 的默认实现
+type HTTPServer struct {
+	router
+
+	// handleMethodNotAllowed 控制命中路径但是方法不匹配时的行为
+	// 为 true 时返回 405 Method Not Allowed 并附带 Allow 头
+	// 为 false（默认）时和普通的 404 一样处理
+	handleMethodNotAllowed bool
+
+	// notFoundHandler 404 的自定义处理逻辑，不设置则使用默认行为
+	notFoundHandler HandleFunc
+	// methodNotAllowedHandler 405 的自定义处理逻辑，不设置则使用默认行为
+	methodNotAllowedHandler HandleFunc
+
+	// redirectTrailingSlash 命中失败时尝试去掉请求路径末尾多余的 /，命中后发起重定向
+	redirectTrailingSlash bool
+	// redirectFixedPath 命中失败时清理路径并忽略大小写重试，命中后发起重定向
+	redirectFixedPath bool
+
+	// middlewares 全局中间件，在注册路由时组合进 HandleFunc，
+	// 只作用于 Use 之后直接通过 Server 注册的路由，以及 Use 之后通过 Group 创建的分组
+	middlewares []Middleware
+
+	// contextPool 池化 Context，避免每个请求都重新分配
+	contextPool sync.Pool
+}
+
+// ServerOption 用于在初始化 HTTPServer 的时候传入可选配置
+type ServerOption func(server *HTTPServer)
+
+// WithHandleMethodNotAllowed 开启 405 Method Not Allowed 支持
+func WithHandleMethodNotAllowed() ServerOption {
+	return func(server *HTTPServer) {
+		server.handleMethodNotAllowed = true
+	}
+}
+
+// WithNotFoundHandler 自定义 404 响应
+func WithNotFoundHandler(handler HandleFunc) ServerOption {
+	return func(server *HTTPServer) {
+		server.notFoundHandler = handler
+	}
+}
+
+// WithMethodNotAllowedHandler 自定义 405 响应
+func WithMethodNotAllowedHandler(handler HandleFunc) ServerOption {
+	return func(server *HTTPServer) {
+		server.methodNotAllowedHandler = handler
+	}
+}
+
+// WithRedirectTrailingSlash 开启末尾斜杠的自动重定向，
+// 例如注册了 /user 时访问 /user/ 会被 301/308 重定向到 /user。
+// 路由不能以 / 结尾，所以只处理请求多了末尾 / 的情况，不会给请求补上 /
+func WithRedirectTrailingSlash() ServerOption {
+	return func(server *HTTPServer) {
+		server.redirectTrailingSlash = true
+	}
+}
+
+// WithRedirectFixedPath 开启路径清理和大小写不敏感匹配后的自动重定向，
+// 例如 /USER//profile 会被重定向到 /user/profile
+func WithRedirectFixedPath() ServerOption {
+	return func(server *HTTPServer) {
+		server.redirectFixedPath = true
+	}
+}
+
+func NewHTTPServer(opts ...ServerOption) *HTTPServer {
+	s := &HTTPServer{
+		router: newRouter(),
+	}
+	s.contextPool.New = func() any {
+		// 按照目前见过的最多路径参数数量预分配，减少请求处理时 append 触发的扩容
+		return &Context{PathParams: make([]Param, 0, s.router.maxParams)}
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Use 注册全局中间件，作用于之后通过 Server 注册的路由以及之后创建的所有 RouterGroup，
+// 已经注册的路由和已经创建的分组不受影响
+func (s *HTTPServer) Use(mws ...Middleware) {
+	s.middlewares = append(s.middlewares, mws...)
+}
+
+// Group 基于 prefix 创建一个 RouterGroup，继承当前已注册的全局中间件
+func (s *HTTPServer) Group(prefix string) *RouterGroup {
+	return &RouterGroup{
+		prefix:      prefix,
+		server:      s,
+		middlewares: append([]Middleware{}, s.middlewares...),
+	}
+}
+
+func (s *HTTPServer) Get(path string, handler HandleFunc, opts ...RouteOption) {
+	s.addRoute(http.MethodGet, path, applyMiddlewares(handler, s.middlewares), opts...)
+}
+
+func (s *HTTPServer) Post(path string, handler HandleFunc, opts ...RouteOption) {
+	s.addRoute(http.MethodPost, path, applyMiddlewares(handler, s.middlewares), opts...)
+}
+
+func (s *HTTPServer) Put(path string, handler HandleFunc, opts ...RouteOption) {
+	s.addRoute(http.MethodPut, path, applyMiddlewares(handler, s.middlewares), opts...)
+}
+
+func (s *HTTPServer) Delete(path string, handler HandleFunc, opts ...RouteOption) {
+	s.addRoute(http.MethodDelete, path, applyMiddlewares(handler, s.middlewares), opts...)
+}
+
+// URLFor 根据 WithName 注册的路由名字反向生成 URL，常用来在模板或者 JSON 响应里拼接链接
+func (s *HTTPServer) URLFor(name string, params map[string]string) (string, error) {
+	return s.router.urlFor(name, params)
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := s.contextPool.Get().(*Context)
+	ctx.Req = r
+	ctx.Resp = w
+	s.serve(ctx)
+	s.releaseContext(ctx)
+}
+
+func (s *HTTPServer) serve(ctx *Context) {
+	info, ok := s.findRouteWithParams(ctx.Req.Method, ctx.Req.URL.Path, ctx.PathParams)
+	if !ok || info.n == nil || info.n.handler == nil {
+		s.serveNotFoundOrMethodNotAllowed(ctx)
+		return
+	}
+
+	ctx.PathParams = info.pathParams
+	info.n.handler(ctx)
+}
+
+// releaseContext 在请求处理完毕之后重置 Context 并放回 pool，
+// 切片类型的字段只清空长度，保留底层数组的容量以便下一次复用
+func (s *HTTPServer) releaseContext(ctx *Context) {
+	ctx.Req = nil
+	ctx.Resp = nil
+	ctx.PathParams = ctx.PathParams[:0]
+	ctx.cacheQueryValues = nil
+	s.contextPool.Put(ctx)
+}
+
+func (s *HTTPServer) serveNotFoundOrMethodNotAllowed(ctx *Context) {
+	method, path := ctx.Req.Method, ctx.Req.URL.Path
+
+	if s.redirectTrailingSlash {
+		if fixed, ok := s.findTrailingSlashRedirect(method, path); ok {
+			s.redirect(ctx, fixed)
+			return
+		}
+	}
+
+	if s.redirectFixedPath {
+		if fixed, ok := s.findFixedPathRedirect(method, path); ok && fixed != path {
+			s.redirect(ctx, fixed)
+			return
+		}
+	}
+
+	if s.handleMethodNotAllowed {
+		if allowed := s.findAllowedMethods(ctx.Req.Method, ctx.Req.URL.Path); len(allowed) > 0 {
+			ctx.Resp.Header().Set("Allow", strings.Join(allowed, ", "))
+			if s.methodNotAllowedHandler != nil {
+				s.methodNotAllowedHandler(ctx)
+				return
+			}
+			ctx.Resp.WriteHeader(http.StatusMethodNotAllowed)
+			_, _ = ctx.Resp.Write([]byte("METHOD NOT ALLOWED"))
+			return
+		}
+	}
+
+	if s.notFoundHandler != nil {
+		s.notFoundHandler(ctx)
+		return
+	}
+	ctx.Resp.WriteHeader(http.StatusNotFound)
+	_, _ = ctx.Resp.Write([]byte("NOT FOUND"))
+}
+
+// redirect 按照 httprouter/gin 的约定选择重定向状态码：
+// GET/HEAD 用 301（允许客户端把方法改写成 GET），其它方法用 308（禁止改写方法）
+func (s *HTTPServer) redirect(ctx *Context, path string) {
+	code := http.StatusPermanentRedirect
+	if ctx.Req.Method == http.MethodGet || ctx.Req.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	http.Redirect(ctx.Resp, ctx.Req, path, code)
+}
+
+func (s *HTTPServer) Start(addr string) error {
+	return http.ListenAndServe(addr, s)
+}
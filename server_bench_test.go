@@ -0,0 +1,28 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// BenchmarkServeHTTP 覆盖两个路径参数 + 一个 query 参数的典型场景，
+// 用来衡量 Context/pathParams 池化之后 ServeHTTP 的 allocs/op。
+// 跑法：go test -bench=ServeHTTP -benchmem
+func BenchmarkServeHTTP(b *testing.B) {
+	s := NewHTTPServer()
+	s.Get("/user/:id/order/:oid", func(ctx *Context) {
+		_, _ = ctx.PathValue("id").String()
+		_, _ = ctx.PathValue("oid").String()
+		_, _ = ctx.QueryValue("page").String()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/user/123/order/456?page=2", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,4 @@
+package web
+
+// HandleFunc 是业务使用的处理函数
+type HandleFunc func(ctx *Context)
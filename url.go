@@ -0,0 +1,84 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URL 根据注册路由时使用的原始 path（例如 /users/:id(\d+)/posts/:pid）和具体的参数值，
+// 反向生成一个可以直接使用的 URL。path 必须和调用 addRoute 时传入的完全一致。
+// 缺少必填参数，或者参数值不满足正则约束，都会返回 error。
+func (r *router) URL(method string, path string, params map[string]string) (string, error) {
+	root, ok := r.trees[method]
+	if !ok {
+		return "", fmt.Errorf("web: 方法 [%s] 没有注册任何路由", method)
+	}
+
+	if path == "/" {
+		if root.handler == nil {
+			return "", fmt.Errorf("web: 路由未注册 [%s] %s", method, path)
+		}
+		return "/", nil
+	}
+
+	segs := strings.Split(path[1:], "/")
+	built := make([]string, 0, len(segs))
+	cur := root
+	for _, s := range segs {
+		switch {
+		case s == "*":
+			if cur.starChild == nil {
+				return "", fmt.Errorf("web: 路由未注册 [%s] %s", method, path)
+			}
+			cur = cur.starChild
+			val, ok := params["*"]
+			if !ok {
+				return "", fmt.Errorf("web: 缺少路径参数 [*]")
+			}
+			built = append(built, val)
+		case s[0] == ':':
+			if cur.regChild != nil && cur.regChild.path == s {
+				cur = cur.regChild
+				val, ok := params[cur.paramName]
+				if !ok {
+					return "", fmt.Errorf("web: 缺少路径参数 [%s]", cur.paramName)
+				}
+				if !cur.regExpr.MatchString(val) {
+					return "", fmt.Errorf("web: 路径参数 [%s] 的值 [%s] 不满足正则 [%s]", cur.paramName, val, cur.regExpr.String())
+				}
+				built = append(built, val)
+			} else if cur.paramChild != nil && cur.paramChild.path == s {
+				cur = cur.paramChild
+				val, ok := params[cur.paramName]
+				if !ok {
+					return "", fmt.Errorf("web: 缺少路径参数 [%s]", cur.paramName)
+				}
+				built = append(built, val)
+			} else {
+				return "", fmt.Errorf("web: 路由未注册 [%s] %s", method, path)
+			}
+		default:
+			child, ok := cur.children[s]
+			if !ok {
+				return "", fmt.Errorf("web: 路由未注册 [%s] %s", method, path)
+			}
+			cur = child
+			built = append(built, s)
+		}
+	}
+
+	if cur.handler == nil {
+		return "", fmt.Errorf("web: 路由未注册 [%s] %s", method, path)
+	}
+
+	return "/" + strings.Join(built, "/"), nil
+}
+
+// urlFor 根据 WithName 注册的路由名字反向生成 URL
+func (r *router) urlFor(name string, params map[string]string) (string, error) {
+	n, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("web: 找不到名为 [%s] 的路由", name)
+	}
+	return r.URL(n.method, n.fullPath, params)
+}
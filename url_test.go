@@ -0,0 +1,87 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	r := newRouter()
+	r.addRoute(http.MethodGet, "/users/:id(^[0-9]+$)/posts/:pid", func(ctx *Context) {}, WithName("user.post"))
+	r.addRoute(http.MethodGet, "/static/*", func(ctx *Context) {})
+
+	testCases := []struct {
+		name    string
+		method  string
+		path    string
+		params  map[string]string
+		wantURL string
+		wantErr bool
+	}{
+		{
+			name:    "正则和参数路由",
+			method:  http.MethodGet,
+			path:    "/users/:id(^[0-9]+$)/posts/:pid",
+			params:  map[string]string{"id": "123", "pid": "abc"},
+			wantURL: "/users/123/posts/abc",
+		},
+		{
+			name:    "正则不匹配",
+			method:  http.MethodGet,
+			path:    "/users/:id(^[0-9]+$)/posts/:pid",
+			params:  map[string]string{"id": "not-a-number", "pid": "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "缺少参数",
+			method:  http.MethodGet,
+			path:    "/users/:id(^[0-9]+$)/posts/:pid",
+			params:  map[string]string{"id": "123"},
+			wantErr: true,
+		},
+		{
+			name:    "通配符路由",
+			method:  http.MethodGet,
+			path:    "/static/*",
+			params:  map[string]string{"*": "js/app.js"},
+			wantURL: "/static/js/app.js",
+		},
+		{
+			name:    "路由未注册",
+			method:  http.MethodGet,
+			path:    "/does/not/exist",
+			params:  map[string]string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.URL(tc.method, tc.path, tc.params)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil, url = %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.wantURL {
+				t.Fatalf("want %s, got %s", tc.wantURL, got)
+			}
+		})
+	}
+
+	got, err := r.urlFor("user.post", map[string]string{"id": "1", "pid": "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "/users/1/posts/2" {
+		t.Fatalf("want /users/1/posts/2, got %s", got)
+	}
+
+	if _, err := r.urlFor("no.such.route", nil); err == nil {
+		t.Fatal("expected error for unknown route name")
+	}
+}